@@ -29,4 +29,6 @@ type AppDBag struct {
 	JDKMountName          string
 	JDKMountPath          string
 	NodeNamePrefix        string
+	Sinks                 []SinkConfig
+	JobManagedByFilter    []string
 }
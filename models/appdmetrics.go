@@ -16,6 +16,8 @@ const METRIC_SEPARATOR string = "|"
 const METRIC_PATH_NODES string = "Nodes"
 const METRIC_PATH_NAMESPACES string = "Namespaces"
 const METRIC_PATH_APPS string = "Pods"
+const METRIC_PATH_JOBS string = "Jobs"
+const METRIC_PATH_MANAGED_BY string = "ManagedBy"
 
 type AppDMetric struct {
 	MetricName              string
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type JobSchema struct {
+	ClusterName string `appd:"name=clusterName,type=string,indexed=true"`
+	Name        string `appd:"name=name,type=string,indexed=true"`
+	Namespace   string `appd:"name=namespace,type=string,indexed=true"`
+	Labels      string `appd:"name=labels,type=string"`
+	Annotations string `appd:"name=annotations,type=string"`
+
+	Active  int32 `appd:"name=active,type=integer"`
+	Success int32 `appd:"name=success,type=integer"`
+	Failed  int32 `appd:"name=failed,type=integer"`
+
+	StartTime             time.Time `appd:"name=startTime,type=date"`
+	EndTime               time.Time `appd:"name=endTime,type=date"`
+	Duration              float64   `appd:"name=duration,type=float,indexed=true"`
+	ActiveDeadlineSeconds int64     `appd:"name=activeDeadlineSeconds,type=integer"`
+	Completions           int32     `appd:"name=completions,type=integer"`
+	Parallelism           int32     `appd:"name=parallelism,type=integer"`
+	BackoffLimit          int32     `appd:"name=backoffLimit,type=integer"`
+
+	CronJobName       string    `appd:"name=cronJobName,type=string,indexed=true"`
+	Schedule          string    `appd:"name=schedule,type=string"`
+	ConcurrencyPolicy string    `appd:"name=concurrencyPolicy,type=string"`
+	LastScheduleTime  time.Time `appd:"name=lastScheduleTime,type=date"`
+
+	ManagedBy string `appd:"name=managedBy,type=string,indexed=true"`
+}
+
+func NewJobObj() JobSchema {
+	return JobSchema{}
+}
+
+type ClusterJobMetrics struct {
+	Namespace string
+	Path      string
+	Metadata  string
+
+	JobCount     int64
+	ActiveCount  int64
+	SuccessCount int64
+	FailedCount  int64
+	Duration     int64
+
+	CompletedCount         int64
+	CompletionSecondsTotal int64
+	AvgCompletionSeconds   int64
+	JobsFailedCount        int64
+	BackoffExceededCount   int64
+
+	DeferredCount int64
+	DroppedCount  int64
+}
+
+// NewClusterJobMetrics builds an empty metrics bucket for the given namespace
+// and kind. kind is ALL for the native job-controller counters and the
+// managed-by value (e.g. "kueue.x-k8s.io/multikueue") for an externally
+// managed bucket; in the latter case it's folded into Path so externally
+// managed Jobs never collide with the native per-namespace metric path or
+// with each other.
+func NewClusterJobMetrics(bag *AppDBag, kind string, namespace string) ClusterJobMetrics {
+	path := fmt.Sprintf(RootPath, bag.AppName) + METRIC_PATH_JOBS + METRIC_SEPARATOR
+	if namespace != ALL {
+		path = path + METRIC_PATH_NAMESPACES + METRIC_SEPARATOR + namespace + METRIC_SEPARATOR
+	}
+	if kind != ALL {
+		path = path + METRIC_PATH_MANAGED_BY + METRIC_SEPARATOR + kind + METRIC_SEPARATOR
+	}
+	return ClusterJobMetrics{Namespace: namespace, Path: path, Metadata: kind}
+}
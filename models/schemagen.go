@@ -0,0 +1,103 @@
+package models
+
+import (
+	"encoding/json"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// SchemaField describes one column of an AppD Analytics event schema.
+type SchemaField struct {
+	Type    string `json:"type"`
+	Indexed bool   `json:"indexed,omitempty"`
+}
+
+// schemaDef is the JSON shape the AppD Events API expects for both creating
+// and patching an Analytics schema.
+type schemaDef struct {
+	Schema map[string]SchemaField `json:"schema"`
+}
+
+// GenerateSchemaDef reflects over obj (a *JobSchema, *PodSchema, *ContainerSchema, ...)
+// and builds the AppD Analytics schema definition from each field's `appd`
+// struct tag, e.g. `appd:"name=duration,type=float,indexed=true"`. Fields
+// without an `appd` tag are left out of the schema. This keeps the Analytics
+// schema in lock-step with the Go struct instead of requiring a hand-maintained
+// JSON payload to be updated alongside every new field.
+func GenerateSchemaDef(obj interface{}) map[string]SchemaField {
+	def := make(map[string]SchemaField)
+
+	t := reflect.TypeOf(obj)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		tag, ok := t.Field(i).Tag.Lookup("appd")
+		if !ok {
+			continue
+		}
+
+		name, field := parseAppDTag(tag)
+		if name == "" {
+			name = t.Field(i).Name
+		}
+		def[name] = field
+	}
+
+	return def
+}
+
+func parseAppDTag(tag string) (string, SchemaField) {
+	var name string
+	field := SchemaField{Type: "string"}
+
+	for _, part := range strings.Split(tag, ",") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key, val := strings.TrimSpace(kv[0]), strings.TrimSpace(kv[1])
+		switch key {
+		case "name":
+			name = val
+		case "type":
+			field.Type = val
+		case "indexed":
+			field.Indexed, _ = strconv.ParseBool(val)
+		}
+	}
+
+	return name, field
+}
+
+// MarshalSchemaDef renders a schema definition generated by GenerateSchemaDef
+// into the JSON payload the AppD Events API expects for CreateSchema/PatchSchema.
+func MarshalSchemaDef(def map[string]SchemaField) ([]byte, error) {
+	return json.Marshal(schemaDef{Schema: def})
+}
+
+// UnmarshalSchemaDef parses a schema definition as returned by the AppD Events
+// API (RestClient.GetSchema) back into the same shape GenerateSchemaDef produces.
+func UnmarshalSchemaDef(data []byte) (map[string]SchemaField, error) {
+	var parsed schemaDef
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return nil, err
+	}
+	return parsed.Schema, nil
+}
+
+// DiffSchemaDef compares a freshly generated schema definition against the
+// remote one and returns only the fields that are new or whose definition
+// changed, so callers can PATCH just the delta instead of re-creating the
+// whole schema on every field addition.
+func DiffSchemaDef(generated map[string]SchemaField, remote map[string]SchemaField) map[string]SchemaField {
+	delta := make(map[string]SchemaField)
+	for name, field := range generated {
+		if existing, ok := remote[name]; !ok || existing != field {
+			delta[name] = field
+		}
+	}
+	return delta
+}
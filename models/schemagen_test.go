@@ -0,0 +1,55 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiffSchemaDef(t *testing.T) {
+	cases := []struct {
+		name      string
+		generated map[string]SchemaField
+		remote    map[string]SchemaField
+		want      map[string]SchemaField
+	}{
+		{
+			name:      "identical schemas produce no delta",
+			generated: map[string]SchemaField{"name": {Type: "string", Indexed: true}},
+			remote:    map[string]SchemaField{"name": {Type: "string", Indexed: true}},
+			want:      map[string]SchemaField{},
+		},
+		{
+			name:      "new field is included in the delta",
+			generated: map[string]SchemaField{"name": {Type: "string"}, "duration": {Type: "float", Indexed: true}},
+			remote:    map[string]SchemaField{"name": {Type: "string"}},
+			want:      map[string]SchemaField{"duration": {Type: "float", Indexed: true}},
+		},
+		{
+			name:      "changed field type is included in the delta",
+			generated: map[string]SchemaField{"active": {Type: "integer"}},
+			remote:    map[string]SchemaField{"active": {Type: "string"}},
+			want:      map[string]SchemaField{"active": {Type: "integer"}},
+		},
+		{
+			name:      "changed indexed flag is included in the delta",
+			generated: map[string]SchemaField{"namespace": {Type: "string", Indexed: true}},
+			remote:    map[string]SchemaField{"namespace": {Type: "string", Indexed: false}},
+			want:      map[string]SchemaField{"namespace": {Type: "string", Indexed: true}},
+		},
+		{
+			name:      "field only present remotely is not reported as a delta",
+			generated: map[string]SchemaField{},
+			remote:    map[string]SchemaField{"legacy": {Type: "string"}},
+			want:      map[string]SchemaField{},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := DiffSchemaDef(c.generated, c.remote)
+			if !reflect.DeepEqual(got, c.want) {
+				t.Errorf("DiffSchemaDef(%v, %v) = %v, want %v", c.generated, c.remote, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package models
+
+// SinkType identifies which backend a SinkConfig configures.
+type SinkType string
+
+const (
+	SinkTypeAppD       SinkType = "appd"
+	SinkTypePrometheus SinkType = "prometheus-remote-write"
+	SinkTypeOTLP       SinkType = "otlp"
+)
+
+// SinkConfig configures one metrics/events destination. When AppDBag.Sinks
+// is empty the cluster-agent publishes to AppD only; once it lists any
+// entries, only those sinks receive data, and AppD is included only if an
+// entry of type SinkTypeAppD is present.
+type SinkConfig struct {
+	Type        SinkType
+	Endpoint    string
+	RetryBudget int
+}
@@ -0,0 +1,34 @@
+package sinks
+
+import (
+	app "github.com/sjeltuhin/clusterAgent/appd"
+	m "github.com/sjeltuhin/clusterAgent/models"
+)
+
+// AppDSink publishes to the AppDynamics Controller/Events API, the
+// cluster-agent's original and still-default sink.
+type AppDSink struct {
+	Controller  *app.ControllerClient
+	RestClient  *app.RestClient
+	RetryBudget int
+}
+
+func NewAppDSink(controller *app.ControllerClient, rc *app.RestClient, retryBudget int) *AppDSink {
+	return &AppDSink{Controller: controller, RestClient: rc, RetryBudget: retryBudget}
+}
+
+func (s *AppDSink) Name() string {
+	return string(m.SinkTypeAppD)
+}
+
+func (s *AppDSink) PublishMetrics(ml m.AppDMetricList) error {
+	return withRetry(s.Name(), s.RetryBudget, func() error {
+		return s.Controller.PostMetrics(ml)
+	})
+}
+
+func (s *AppDSink) PublishEvents(schemaName string, payload []byte) error {
+	return withRetry(s.Name(), s.RetryBudget, func() error {
+		return s.RestClient.PostAppDEvents(schemaName, payload)
+	})
+}
@@ -0,0 +1,42 @@
+package sinks
+
+import (
+	"fmt"
+
+	app "github.com/sjeltuhin/clusterAgent/appd"
+	m "github.com/sjeltuhin/clusterAgent/models"
+)
+
+// BuildSinks constructs the active Sink set from bag.Sinks. When bag.Sinks is
+// empty, the AppD controller/REST client sink is included by default so
+// existing deployments keep working unchanged even if they never configure
+// bag.Sinks. Once bag.Sinks is non-empty, only the sinks listed there run,
+// so clusters that want to experiment with e.g. Prometheus alone can do so
+// without an AppD controller dependency; add an explicit SinkTypeAppD entry
+// to keep AppD alongside the others.
+func BuildSinks(bag *m.AppDBag, controller *app.ControllerClient, rc *app.RestClient) []Sink {
+	var active []Sink
+	if len(bag.Sinks) == 0 {
+		return []Sink{NewAppDSink(controller, rc, 0)}
+	}
+
+	for _, cfg := range bag.Sinks {
+		switch cfg.Type {
+		case m.SinkTypeAppD:
+			active = append(active, NewAppDSink(controller, rc, cfg.RetryBudget))
+		case m.SinkTypePrometheus:
+			active = append(active, NewPrometheusSink(cfg.Endpoint, bag.AppName, cfg.RetryBudget))
+		case m.SinkTypeOTLP:
+			otlpSink, err := NewOTLPSink(cfg.Endpoint, bag.AppName, cfg.RetryBudget)
+			if err != nil {
+				fmt.Printf("Unable to configure OTLP sink at %s: %v\n", cfg.Endpoint, err)
+				continue
+			}
+			active = append(active, otlpSink)
+		default:
+			fmt.Printf("Unknown sink type %q, skipping\n", cfg.Type)
+		}
+	}
+
+	return active
+}
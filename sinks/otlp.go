@@ -0,0 +1,106 @@
+package sinks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/metrics/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+
+	m "github.com/sjeltuhin/clusterAgent/models"
+)
+
+// OTLPSink exports AppDMetrics to an OTLP/gRPC collector as Gauge data
+// points, for clusters standardizing on an OpenTelemetry pipeline instead of
+// an AppD controller.
+type OTLPSink struct {
+	Endpoint    string
+	ClusterName string
+	RetryBudget int
+	client      coltracepb.MetricsServiceClient
+}
+
+func NewOTLPSink(endpoint string, clusterName string, retryBudget int) (*OTLPSink, error) {
+	conn, err := grpc.Dial(endpoint, grpc.WithInsecure())
+	if err != nil {
+		return nil, fmt.Errorf("unable to dial OTLP collector %s: %v", endpoint, err)
+	}
+
+	return &OTLPSink{
+		Endpoint:    endpoint,
+		ClusterName: clusterName,
+		RetryBudget: retryBudget,
+		client:      coltracepb.NewMetricsServiceClient(conn),
+	}, nil
+}
+
+func (s *OTLPSink) Name() string {
+	return string(m.SinkTypeOTLP)
+}
+
+func (s *OTLPSink) PublishMetrics(ml m.AppDMetricList) error {
+	req := &coltracepb.ExportMetricsServiceRequest{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: &resourcepb.Resource{
+				Attributes: []*commonpb.KeyValue{{
+					Key:   "k8s.cluster.name",
+					Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: s.ClusterName}},
+				}},
+			},
+			ScopeMetrics: []*metricspb.ScopeMetrics{{Metrics: toOTLPMetrics(ml)}},
+		}},
+	}
+
+	return withRetry(s.Name(), s.RetryBudget, func() error {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		_, err := s.client.Export(ctx, req)
+		return err
+	})
+}
+
+// PublishEvents is a no-op: OTLP's metrics export path has no analogue for
+// the structured Job/Pod event records AppD Analytics stores.
+func (s *OTLPSink) PublishEvents(schemaName string, payload []byte) error {
+	return nil
+}
+
+func toOTLPMetrics(ml m.AppDMetricList) []*metricspb.Metric {
+	now := uint64(time.Now().UnixNano())
+	metrics := make([]*metricspb.Metric, 0, len(ml.Items))
+	for _, metric := range ml.Items {
+		metrics = append(metrics, &metricspb.Metric{
+			Name: sanitizeMetricName(metric.MetricName),
+			Data: &metricspb.Metric_Gauge{
+				Gauge: &metricspb.Gauge{
+					DataPoints: []*metricspb.NumberDataPoint{{
+						TimeUnixNano: now,
+						Attributes:   toOTLPAttributes(metric.MetricPath),
+						Value:        &metricspb.NumberDataPoint_AsInt{AsInt: metric.MetricValue},
+					}},
+				},
+			},
+		})
+	}
+	return metrics
+}
+
+// toOTLPAttributes mirrors the Prometheus sink's label derivation so
+// namespace, kind and managed-by stay distinct dimensions instead of
+// collapsing every series for a metric name into one unlabeled gauge.
+func toOTLPAttributes(path string) []*commonpb.KeyValue {
+	pathLabels := metricPathLabels(path)
+	attrs := make([]*commonpb.KeyValue, 0, len(pathLabels))
+	for name, value := range pathLabels {
+		attrs = append(attrs, &commonpb.KeyValue{
+			Key:   name,
+			Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+		})
+	}
+	return attrs
+}
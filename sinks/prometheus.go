@@ -0,0 +1,108 @@
+package sinks
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/golang/snappy"
+	"github.com/prometheus/prometheus/prompb"
+
+	m "github.com/sjeltuhin/clusterAgent/models"
+)
+
+// PrometheusSink converts AppDMetrics into labeled Prometheus time series and
+// ships them via remote_write, for clusters that already run a Prometheus
+// pipeline and don't want an AppD controller dependency.
+type PrometheusSink struct {
+	Endpoint    string
+	ClusterName string
+	RetryBudget int
+	Client      *http.Client
+}
+
+func NewPrometheusSink(endpoint string, clusterName string, retryBudget int) *PrometheusSink {
+	return &PrometheusSink{
+		Endpoint:    endpoint,
+		ClusterName: clusterName,
+		RetryBudget: retryBudget,
+		Client:      &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (s *PrometheusSink) Name() string {
+	return string(m.SinkTypePrometheus)
+}
+
+func (s *PrometheusSink) PublishMetrics(ml m.AppDMetricList) error {
+	req := &prompb.WriteRequest{Timeseries: make([]prompb.TimeSeries, 0, len(ml.Items))}
+	for _, metric := range ml.Items {
+		req.Timeseries = append(req.Timeseries, s.toTimeSeries(metric))
+	}
+
+	return withRetry(s.Name(), s.RetryBudget, func() error {
+		return s.send(req)
+	})
+}
+
+// PublishEvents is a no-op: remote_write only carries metrics, so Job/Pod
+// event records have nowhere meaningful to land in this sink.
+func (s *PrometheusSink) PublishEvents(schemaName string, payload []byte) error {
+	return nil
+}
+
+// toTimeSeries decomposes an AppD MetricPath, e.g.
+// "Server|Component:my-app|Custom Metrics|Cluster Stats|Jobs|Namespaces|default|JobCount",
+// into cluster/namespace/kind/managed_by labels alongside the metric's own __name__.
+func (s *PrometheusSink) toTimeSeries(metric m.AppDMetric) prompb.TimeSeries {
+	labels := []prompb.Label{
+		{Name: "__name__", Value: sanitizeMetricName(metric.MetricName)},
+		{Name: "cluster", Value: s.ClusterName},
+	}
+	for name, value := range metricPathLabels(metric.MetricPath) {
+		labels = append(labels, prompb.Label{Name: name, Value: value})
+	}
+
+	return prompb.TimeSeries{
+		Labels: labels,
+		Samples: []prompb.Sample{{
+			Value:     float64(metric.MetricValue),
+			Timestamp: time.Now().UnixMilli(),
+		}},
+	}
+}
+
+func sanitizeMetricName(name string) string {
+	replacer := strings.NewReplacer(" ", "_", "-", "_", ".", "_")
+	return "clusteragent_" + strings.ToLower(replacer.Replace(name))
+}
+
+func (s *PrometheusSink) send(req *prompb.WriteRequest) error {
+	data, err := proto.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("unable to marshal remote_write request: %v", err)
+	}
+	compressed := snappy.Encode(nil, data)
+
+	httpReq, err := http.NewRequest(http.MethodPost, s.Endpoint, bytes.NewReader(compressed))
+	if err != nil {
+		return fmt.Errorf("unable to build remote_write request: %v", err)
+	}
+	httpReq.Header.Set("Content-Encoding", "snappy")
+	httpReq.Header.Set("Content-Type", "application/x-protobuf")
+	httpReq.Header.Set("X-Prometheus-Remote-Write-Version", "0.1.0")
+
+	resp, err := s.Client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("remote_write request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("remote_write endpoint returned %s", resp.Status)
+	}
+	return nil
+}
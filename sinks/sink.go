@@ -0,0 +1,92 @@
+// Package sinks decouples the cluster-agent's workers from any single
+// metrics/events backend. A Sink is anything that can take an AppDMetricList
+// or a serialized event payload; workers fan out to every configured Sink
+// instead of calling the AppD controller/REST client directly.
+package sinks
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	m "github.com/sjeltuhin/clusterAgent/models"
+)
+
+// Sink is a destination for the metrics and events the cluster-agent's
+// workers collect. Each implementation owns its own retry policy so one
+// slow or unhealthy sink can't hold up or drop data for the others.
+type Sink interface {
+	Name() string
+	PublishMetrics(ml m.AppDMetricList) error
+	PublishEvents(schemaName string, payload []byte) error
+}
+
+// FanOutMetrics publishes ml to every sink concurrently and waits for all of
+// them to finish. A sink's own error is logged, not returned, so one failing
+// sink never suppresses delivery to the others.
+func FanOutMetrics(sinksList []Sink, ml m.AppDMetricList) {
+	var wg sync.WaitGroup
+	for _, s := range sinksList {
+		wg.Add(1)
+		go func(s Sink) {
+			defer wg.Done()
+			if err := s.PublishMetrics(ml); err != nil {
+				fmt.Printf("Sink %s dropped a metrics batch: %v\n", s.Name(), err)
+			}
+		}(s)
+	}
+	wg.Wait()
+}
+
+// FanOutEvents publishes payload under schemaName to every sink concurrently.
+func FanOutEvents(sinksList []Sink, schemaName string, payload []byte) {
+	var wg sync.WaitGroup
+	for _, s := range sinksList {
+		wg.Add(1)
+		go func(s Sink) {
+			defer wg.Done()
+			if err := s.PublishEvents(schemaName, payload); err != nil {
+				fmt.Printf("Sink %s dropped an events batch for schema %s: %v\n", s.Name(), schemaName, err)
+			}
+		}(s)
+	}
+	wg.Wait()
+}
+
+// metricPathLabels decomposes an AppD MetricPath, e.g.
+// "Server|Component:my-app|Custom Metrics|Cluster Stats|Jobs|Namespaces|default|ManagedBy|kueue.x-k8s.io|JobCount",
+// into the namespace/kind/managed_by dimensions every sink needs in order to
+// keep per-namespace and per-managed-by series distinct instead of aliasing
+// onto the same label set.
+func metricPathLabels(path string) map[string]string {
+	labels := make(map[string]string)
+	segments := strings.Split(strings.Trim(path, m.METRIC_SEPARATOR), m.METRIC_SEPARATOR)
+	for i, segment := range segments {
+		switch segment {
+		case m.METRIC_PATH_NAMESPACES:
+			if i+1 < len(segments) {
+				labels["namespace"] = segments[i+1]
+			}
+		case m.METRIC_PATH_MANAGED_BY:
+			if i+1 < len(segments) {
+				labels["managed_by"] = segments[i+1]
+			}
+		case m.METRIC_PATH_JOBS, m.METRIC_PATH_NODES, m.METRIC_PATH_APPS:
+			labels["kind"] = segment
+		}
+	}
+	return labels
+}
+
+// withRetry calls publish up to budget+1 times (one attempt plus budget
+// retries), returning nil on the first success and the last error otherwise.
+func withRetry(sinkName string, budget int, publish func() error) error {
+	var err error
+	for attempt := 0; attempt <= budget; attempt++ {
+		if err = publish(); err == nil {
+			return nil
+		}
+		fmt.Printf("Sink %s attempt %d/%d failed: %v\n", sinkName, attempt+1, budget+1, err)
+	}
+	return err
+}
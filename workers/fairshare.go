@@ -0,0 +1,126 @@
+package workers
+
+import (
+	"sync"
+	"time"
+)
+
+// tokenBucket bounds how many items a single namespace may dequeue in one
+// flush pass. It refills in proportion to that namespace's own recent
+// submission volume, so a quiet namespace never gets starved by a noisy one
+// and a noisy one can never dequeue beyond its own fair share.
+type tokenBucket struct {
+	tokens   float64
+	capacity float64
+	lastFill time.Time
+}
+
+func newTokenBucket(capacity float64) *tokenBucket {
+	return &tokenBucket{tokens: capacity, capacity: capacity, lastFill: time.Now()}
+}
+
+func (b *tokenBucket) refill(now time.Time, capacity float64) {
+	b.capacity = capacity
+	if b.tokens < capacity {
+		b.tokens = capacity
+	}
+	b.lastFill = now
+}
+
+func (b *tokenBucket) take(n float64) float64 {
+	taken := minFloat(b.tokens, n)
+	b.tokens -= taken
+	return taken
+}
+
+func minFloat(a, b float64) float64 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// fairShareScheduler implements an Armada-style ProtectedFractionOfFairShare
+// policy in front of JobsWorker's event workqueue: it tracks each
+// namespace's recent submission rate and, every flush pass, lets each
+// namespace dequeue at most its fair share of the pass's budget. Allowance a
+// namespace leaves unused is not passed on to other namespaces, so no
+// namespace ever dequeues beyond its own fair share in a pass.
+type fairShareScheduler struct {
+	mu      sync.Mutex
+	buckets map[string]*tokenBucket
+	volume  map[string]float64 // exponentially decayed recent submission rate, per namespace
+	backlog map[string]int     // records currently sitting in JobsWorker's WQ, per namespace
+}
+
+func newFairShareScheduler() *fairShareScheduler {
+	return &fairShareScheduler{
+		buckets: make(map[string]*tokenBucket),
+		volume:  make(map[string]float64),
+		backlog: make(map[string]int),
+	}
+}
+
+// trackEnqueue records that n records for namespace were just added to the
+// shared WQ, which has no per-namespace notion of its own length.
+func (s *fairShareScheduler) trackEnqueue(namespace string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backlog[namespace] += n
+}
+
+// trackDequeue records that n records for namespace just left the shared WQ.
+func (s *fairShareScheduler) trackDequeue(namespace string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.backlog[namespace] -= n
+}
+
+// backlogLen returns how many records for namespace are currently believed
+// to be sitting in the shared WQ.
+func (s *fairShareScheduler) backlogLen(namespace string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.backlog[namespace]
+}
+
+// observe records that n records arrived for namespace this pass, updating
+// its decayed recent-volume estimate.
+func (s *fairShareScheduler) observe(namespace string, n int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	const decay = 0.7
+	s.volume[namespace] = s.volume[namespace]*decay + float64(n)*(1-decay)
+}
+
+// allocate splits budget across namespaces in proportion to each one's
+// recent volume and returns how many records each namespace may dequeue
+// this pass.
+func (s *fairShareScheduler) allocate(budget int, namespaces []string) map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var totalVolume float64
+	for _, ns := range namespaces {
+		totalVolume += s.volume[ns]
+	}
+
+	now := time.Now()
+	allowance := make(map[string]int, len(namespaces))
+	for _, ns := range namespaces {
+		share := 1.0 / float64(len(namespaces))
+		if totalVolume > 0 {
+			share = s.volume[ns] / totalVolume
+		}
+		fairShare := float64(budget) * share
+
+		bucket, ok := s.buckets[ns]
+		if !ok {
+			bucket = newTokenBucket(fairShare)
+			s.buckets[ns] = bucket
+		}
+		bucket.refill(now, fairShare)
+		allowance[ns] = int(bucket.take(fairShare))
+	}
+	return allowance
+}
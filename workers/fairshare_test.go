@@ -0,0 +1,47 @@
+package workers
+
+import "testing"
+
+func TestFairShareSchedulerAllocate(t *testing.T) {
+	t.Run("no observed volume splits the budget evenly", func(t *testing.T) {
+		s := newFairShareScheduler()
+		got := s.allocate(100, []string{"a", "b"})
+		if got["a"] != 50 || got["b"] != 50 {
+			t.Errorf("allocate() = %v, want a=50 b=50", got)
+		}
+	})
+
+	t.Run("a single namespace gets the whole budget", func(t *testing.T) {
+		s := newFairShareScheduler()
+		s.observe("a", 42)
+		got := s.allocate(100, []string{"a"})
+		if got["a"] != 100 {
+			t.Errorf("allocate() = %v, want a=100", got)
+		}
+	})
+
+	t.Run("allocation is proportional to recent observed volume", func(t *testing.T) {
+		s := newFairShareScheduler()
+		s.observe("noisy", 100)
+		s.observe("quiet", 10)
+
+		got := s.allocate(100, []string{"noisy", "quiet"})
+		if got["noisy"] <= got["quiet"] {
+			t.Errorf("allocate() = %v, want noisy > quiet", got)
+		}
+		if got["noisy"] != 90 || got["quiet"] != 9 {
+			t.Errorf("allocate() = %v, want noisy=90 quiet=9", got)
+		}
+	})
+
+	t.Run("a namespace cannot dequeue beyond its own fair share in one pass", func(t *testing.T) {
+		s := newFairShareScheduler()
+		s.observe("a", 1)
+		s.observe("b", 1)
+
+		got := s.allocate(10, []string{"a", "b"})
+		if got["a"] > 5 || got["b"] > 5 {
+			t.Errorf("allocate() = %v, want neither namespace above its 5-token fair share", got)
+		}
+	})
+}
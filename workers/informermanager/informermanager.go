@@ -0,0 +1,112 @@
+// Package informermanager provides a single shared informer per Kubernetes
+// resource kind so that the various cluster-agent workers (pods, nodes,
+// namespaces, jobs, ...) stop each running their own SharedIndexInformer
+// against the same GroupVersionResource. Workers subscribe to the GVRs they
+// care about and get back a lister and a sync check; the manager takes care
+// of lazily creating the informer, multiplexing AddEventHandler callbacks
+// across every subscriber, and running exactly one list/watch per GVR.
+package informermanager
+
+import (
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/cache"
+)
+
+// GVRs for the resource kinds the cluster-agent's workers observe.
+var (
+	PodsGVR         = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "pods"}
+	NodesGVR        = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "nodes"}
+	NamespacesGVR   = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "namespaces"}
+	EndpointsGVR    = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "endpoints"}
+	ServicesGVR     = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "services"}
+	EventsGVR       = schema.GroupVersionResource{Group: "", Version: "v1", Resource: "events"}
+	DeploymentsGVR  = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}
+	DaemonSetsGVR   = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "daemonsets"}
+	StatefulSetsGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "statefulsets"}
+	JobsGVR         = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+	CronJobsGVR     = schema.GroupVersionResource{Group: "batch", Version: "v1beta1", Resource: "cronjobs"}
+)
+
+// InformerManager lazily creates one dynamic SharedIndexInformer per GVR and
+// reference-counts the workers subscribed to it, so the API server only ever
+// sees a single list/watch per resource kind regardless of how many workers
+// are interested in it.
+type InformerManager struct {
+	factory dynamicinformer.DynamicSharedInformerFactory
+
+	mu       sync.Mutex
+	refCount map[schema.GroupVersionResource]int
+	started  bool
+}
+
+// NewInformerManager builds an InformerManager backed by a dynamic client for config.
+func NewInformerManager(config *rest.Config) (*InformerManager, error) {
+	dynamicClient, err := dynamic.NewForConfig(config)
+	if err != nil {
+		return nil, fmt.Errorf("unable to build dynamic client for InformerManager: %v", err)
+	}
+
+	return &InformerManager{
+		factory:  dynamicinformer.NewDynamicSharedInformerFactory(dynamicClient, 0),
+		refCount: make(map[schema.GroupVersionResource]int),
+	}, nil
+}
+
+// Subscribe registers handler against gvr's informer, creating that informer
+// the first time it's asked for. Call this for every GVR a worker needs
+// before calling Start.
+func (im *InformerManager) Subscribe(gvr schema.GroupVersionResource, handler cache.ResourceEventHandlerFuncs) error {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	informer := im.factory.ForResource(gvr).Informer()
+	if _, err := informer.AddEventHandler(handler); err != nil {
+		return fmt.Errorf("unable to register handler for %s: %v", gvr, err)
+	}
+	im.refCount[gvr]++
+
+	return nil
+}
+
+// Start begins running the informer for every GVR subscribed to so far. It
+// is safe to call only once all workers sharing this manager have subscribed.
+func (im *InformerManager) Start(stopCh <-chan struct{}) {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+
+	im.started = true
+	im.factory.Start(stopCh)
+}
+
+// GetLister returns the generic lister backing gvr's informer.
+func (im *InformerManager) GetLister(gvr schema.GroupVersionResource) cache.GenericLister {
+	return im.factory.ForResource(gvr).Lister()
+}
+
+// HasSynced reports whether gvr's informer cache has completed its initial sync.
+func (im *InformerManager) HasSynced(gvr schema.GroupVersionResource) bool {
+	return im.factory.ForResource(gvr).Informer().HasSynced()
+}
+
+// WaitForCacheSync blocks until every gvr in gvrs has synced or stopCh closes.
+func (im *InformerManager) WaitForCacheSync(stopCh <-chan struct{}, gvrs ...schema.GroupVersionResource) bool {
+	funcs := make([]cache.InformerSynced, 0, len(gvrs))
+	for _, gvr := range gvrs {
+		gvr := gvr
+		funcs = append(funcs, func() bool { return im.HasSynced(gvr) })
+	}
+	return cache.WaitForCacheSync(stopCh, funcs...)
+}
+
+// SubscriberCount returns how many workers have subscribed to gvr, for tests/metrics.
+func (im *InformerManager) SubscriberCount(gvr schema.GroupVersionResource) int {
+	im.mu.Lock()
+	defer im.mu.Unlock()
+	return im.refCount[gvr]
+}
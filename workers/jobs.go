@@ -12,91 +12,174 @@ import (
 	"github.com/fatih/structs"
 
 	m "github.com/sjeltuhin/clusterAgent/models"
+	"github.com/sjeltuhin/clusterAgent/sinks"
+	"github.com/sjeltuhin/clusterAgent/workers/informermanager"
 
 	app "github.com/sjeltuhin/clusterAgent/appd"
 	batchTypes "k8s.io/api/batch/v1"
+	batchTypesV1beta1 "k8s.io/api/batch/v1beta1"
 	"k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
-	batch "k8s.io/client-go/kubernetes/typed/batch/v1"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/util/workqueue"
 )
 
+const cronJobKind string = "CronJob"
+const managedByLabel string = "batch.kubernetes.io/managed-by"
+
+// defaultJobManagedByFilter is used when AppDBag.JobManagedByFilter is unset:
+// an empty label value covers Jobs created directly by a user or controller
+// that doesn't set the label at all, and "job-controller.k8s.io" covers the
+// in-tree Job controller. Anything else is treated as externally managed.
+var defaultJobManagedByFilter = []string{"", "job-controller.k8s.io"}
+
 type JobsWorker struct {
-	informer       cache.SharedIndexInformer
+	Manager        *informermanager.InformerManager
 	Client         *kubernetes.Clientset
 	Bag            *m.AppDBag
 	SummaryMap     map[string]m.ClusterJobMetrics
+	summaryMu      *sync.Mutex // guards SummaryMap: the metrics and event-queue tickers both touch it
 	WQ             workqueue.RateLimitingInterface
 	AppdController *app.ControllerClient
-	K8sConfig      *rest.Config
+	RestClient     *app.RestClient
+	Sinks          []sinks.Sink
+	fairShare      *fairShareScheduler
 }
 
-func NewJobsWorker(client *kubernetes.Clientset, bag *m.AppDBag, controller *app.ControllerClient, config *rest.Config) JobsWorker {
+func NewJobsWorker(client *kubernetes.Clientset, bag *m.AppDBag, controller *app.ControllerClient, manager *informermanager.InformerManager) JobsWorker {
 	queue := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
-	pw := JobsWorker{Client: client, Bag: bag, SummaryMap: make(map[string]m.ClusterJobMetrics), WQ: queue, AppdController: controller, K8sConfig: config}
-	pw.initJobInformer(client)
+	logger := log.New(os.Stdout, "[APPD_CLUSTER_MONITOR]", log.Lshortfile)
+	rc := app.NewRestClient(bag, logger)
+
+	if len(bag.JobManagedByFilter) == 0 {
+		bag.JobManagedByFilter = defaultJobManagedByFilter
+	}
+
+	pw := JobsWorker{
+		Client:         client,
+		Bag:            bag,
+		SummaryMap:     make(map[string]m.ClusterJobMetrics),
+		summaryMu:      &sync.Mutex{},
+		WQ:             queue,
+		AppdController: controller,
+		Manager:        manager,
+		RestClient:     rc,
+		Sinks:          sinks.BuildSinks(bag, controller, rc),
+		fairShare:      newFairShareScheduler(),
+	}
+	pw.subscribe()
 	return pw
 }
 
-func (nw *JobsWorker) initJobInformer(client *kubernetes.Clientset) cache.SharedIndexInformer {
-	batchClient, err := batch.NewForConfig(nw.K8sConfig)
-	if err != nil {
-		fmt.Printf("Issues when initializing Batch API client/ %v", err)
-		return nil
-	}
-
-	i := cache.NewSharedIndexInformer(
-		&cache.ListWatch{
-			ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
-				return batchClient.Jobs(metav1.NamespaceAll).List(options)
-			},
-			WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
-				return batchClient.Jobs(metav1.NamespaceAll).Watch(options)
-			},
-		},
-		&v1.Node{},
-		0,
-		cache.Indexers{cache.NamespaceIndex: cache.MetaNamespaceIndexFunc},
-	)
-
-	i.AddEventHandler(cache.ResourceEventHandlerFuncs{
+// subscribe registers this worker's handlers on the shared InformerManager
+// instead of standing up its own Job/CronJob informers.
+func (nw *JobsWorker) subscribe() {
+	err := nw.Manager.Subscribe(informermanager.JobsGVR, cache.ResourceEventHandlerFuncs{
 		AddFunc:    nw.onNewJob,
 		DeleteFunc: nw.onDeleteJob,
 		UpdateFunc: nw.onUpdateJob,
 	})
-	nw.informer = i
+	if err != nil {
+		fmt.Printf("Unable to subscribe JobsWorker to jobs: %v\n", err)
+	}
 
-	return i
+	// CronJobs are only watched so findCronJobParent can resolve a Job's
+	// owner; this worker doesn't react to CronJob events directly.
+	if err := nw.Manager.Subscribe(informermanager.CronJobsGVR, cache.ResourceEventHandlerFuncs{}); err != nil {
+		fmt.Printf("Unable to subscribe JobsWorker to cronjobs: %v\n", err)
+	}
 }
 
 func (nw *JobsWorker) onNewJob(obj interface{}) {
-	jobObj := obj.(*v1.Node)
+	jobObj, err := toJob(obj)
+	if err != nil {
+		fmt.Printf("Unable to process added Job: %v\n", err)
+		return
+	}
 	fmt.Printf("Added Job: %s\n", jobObj.Name)
-
+	jobSchema := nw.processObject(jobObj)
+	nw.WQ.Add(&jobSchema)
+	nw.fairShare.trackEnqueue(jobSchema.Namespace, 1)
 }
 
 func (nw *JobsWorker) onDeleteJob(obj interface{}) {
-	jobObj := obj.(*v1.Node)
+	jobObj, err := toJob(obj)
+	if err != nil {
+		fmt.Printf("Unable to process deleted Job: %v\n", err)
+		return
+	}
 	fmt.Printf("Deleted Job: %s\n", jobObj.Name)
 }
 
 func (nw *JobsWorker) onUpdateJob(objOld interface{}, objNew interface{}) {
-	jobObj := objOld.(*v1.Node)
+	jobObj, err := toJob(objNew)
+	if err != nil {
+		fmt.Printf("Unable to process updated Job: %v\n", err)
+		return
+	}
 	fmt.Printf("Updated Job: %s\n", jobObj.Name)
+	jobSchema := nw.processObject(jobObj)
+	nw.WQ.Add(&jobSchema)
+	nw.fairShare.trackEnqueue(jobSchema.Namespace, 1)
+}
+
+// toJob converts the unstructured object handed back by the InformerManager's
+// dynamic informers into a typed batch/v1.Job.
+func toJob(obj interface{}) (*batchTypes.Job, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+	}
+	job := &batchTypes.Job{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, job); err != nil {
+		return nil, fmt.Errorf("unable to convert unstructured object to Job: %v", err)
+	}
+	return job, nil
+}
+
+// toCronJob converts the unstructured object handed back by the
+// InformerManager's dynamic informers into a typed batch/v1beta1.CronJob.
+func toCronJob(obj runtime.Object) (*batchTypesV1beta1.CronJob, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, fmt.Errorf("expected *unstructured.Unstructured, got %T", obj)
+	}
+	cronJob := &batchTypesV1beta1.CronJob{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, cronJob); err != nil {
+		return nil, fmt.Errorf("unable to convert unstructured object to CronJob: %v", err)
+	}
+	return cronJob, nil
+}
+
+// findCronJobParent walks the Job's OwnerReferences looking for a CronJob owner
+// and resolves it against the shared CronJob lister's cache.
+func (nw *JobsWorker) findCronJobParent(j *batchTypes.Job) *batchTypesV1beta1.CronJob {
+	for _, ref := range j.OwnerReferences {
+		if ref.Kind != cronJobKind {
+			continue
+		}
+		obj, err := nw.Manager.GetLister(informermanager.CronJobsGVR).ByNamespace(j.Namespace).Get(ref.Name)
+		if err != nil {
+			continue
+		}
+		cronJob, err := toCronJob(obj)
+		if err != nil {
+			continue
+		}
+		return cronJob
+	}
+	return nil
 }
 
 func (pw JobsWorker) Observe(stopCh <-chan struct{}, wg *sync.WaitGroup) {
 	defer wg.Done()
 	defer pw.WQ.ShutDown()
-	wg.Add(1)
-	go pw.informer.Run(stopCh)
 
-	if !cache.WaitForCacheSync(stopCh, pw.HasSynced) {
+	pw.Manager.Start(stopCh)
+	if !pw.Manager.WaitForCacheSync(stopCh, informermanager.JobsGVR, informermanager.CronJobsGVR) {
 		fmt.Errorf("Timed out waiting for caches to sync")
 	}
 	fmt.Println("Cache syncronized. Starting the processing...")
@@ -111,7 +194,7 @@ func (pw JobsWorker) Observe(stopCh <-chan struct{}, wg *sync.WaitGroup) {
 }
 
 func (pw *JobsWorker) HasSynced() bool {
-	return pw.informer.HasSynced()
+	return pw.Manager.HasSynced(informermanager.JobsGVR) && pw.Manager.HasSynced(informermanager.CronJobsGVR)
 }
 
 func (pw *JobsWorker) startMetricsWorker(stopCh <-chan struct{}) {
@@ -133,13 +216,27 @@ func (pw *JobsWorker) appMetricTicker(stop <-chan struct{}, ticker *time.Ticker)
 
 func (pw *JobsWorker) buildAppDMetrics() {
 	bth := pw.AppdController.StartBT("SendJobMetrics")
+	pw.summaryMu.Lock()
 	pw.SummaryMap = make(map[string]m.ClusterJobMetrics)
+	pw.summaryMu.Unlock()
 	fmt.Println("Time to send job metrics. Current cache:")
 	var count int = 0
-	for _, obj := range pw.informer.GetStore().List() {
-		jobObject := obj.(*batchTypes.Job)
+	objs, err := pw.Manager.GetLister(informermanager.JobsGVR).List(labels.Everything())
+	if err != nil {
+		fmt.Printf("Unable to list Jobs: %v\n", err)
+	}
+	for _, obj := range objs {
+		jobObject, err := toJob(obj)
+		if err != nil {
+			fmt.Printf("Unable to process cached Job: %v\n", err)
+			continue
+		}
 		jobSchema := pw.processObject(jobObject)
-		pw.summarize(&jobSchema)
+		if pw.isNativelyManaged(jobObject) {
+			pw.summarize(&jobSchema, jobObject)
+		} else {
+			pw.summarizeExternallyManaged(&jobSchema)
+		}
 		count++
 	}
 	fmt.Printf("Total: %d\n", count)
@@ -148,7 +245,7 @@ func (pw *JobsWorker) buildAppDMetrics() {
 
 	fmt.Printf("Ready to push %d metrics\n", len(ml.Items))
 
-	pw.AppdController.PostMetrics(ml)
+	sinks.FanOutMetrics(pw.Sinks, ml)
 	pw.AppdController.StopBT(bth)
 }
 
@@ -162,6 +259,7 @@ func (pw *JobsWorker) processObject(j *batchTypes.Job) m.JobSchema {
 	}
 	jobObject.Name = j.Name
 	jobObject.Namespace = j.Namespace
+	jobObject.ManagedBy = j.Labels[managedByLabel]
 
 	var sb strings.Builder
 	for k, v := range j.GetLabels() {
@@ -181,24 +279,118 @@ func (pw *JobsWorker) processObject(j *batchTypes.Job) m.JobSchema {
 
 	jobObject.Failed = j.Status.Failed
 
-	jobObject.StartTime = j.Status.StartTime.Time
+	if j.Status.StartTime != nil {
+		jobObject.StartTime = j.Status.StartTime.Time
+	}
 
 	if j.Status.CompletionTime != nil {
 		jobObject.EndTime = j.Status.CompletionTime.Time
 		jobObject.Duration = jobObject.EndTime.Sub(jobObject.StartTime).Seconds()
-	} else {
+	} else if !jobObject.StartTime.IsZero() {
 		jobObject.Duration = time.Since(jobObject.StartTime).Seconds()
 	}
 
-	jobObject.ActiveDeadlineSeconds = *j.Spec.ActiveDeadlineSeconds
-	jobObject.Completions = *j.Spec.Completions
-	jobObject.BackoffLimit = *j.Spec.BackoffLimit
-	jobObject.Parallelism = *j.Spec.Parallelism
+	if j.Spec.ActiveDeadlineSeconds != nil {
+		jobObject.ActiveDeadlineSeconds = *j.Spec.ActiveDeadlineSeconds
+	}
+	if j.Spec.Completions != nil {
+		jobObject.Completions = *j.Spec.Completions
+	}
+	if j.Spec.BackoffLimit != nil {
+		jobObject.BackoffLimit = *j.Spec.BackoffLimit
+	}
+	if j.Spec.Parallelism != nil {
+		jobObject.Parallelism = *j.Spec.Parallelism
+	}
+
+	if cronJob := pw.findCronJobParent(j); cronJob != nil {
+		jobObject.CronJobName = cronJob.Name
+		jobObject.Schedule = cronJob.Spec.Schedule
+		jobObject.ConcurrencyPolicy = string(cronJob.Spec.ConcurrencyPolicy)
+		if cronJob.Status.LastScheduleTime != nil {
+			jobObject.LastScheduleTime = cronJob.Status.LastScheduleTime.Time
+		}
+	}
 
 	return jobObject
 }
 
-func (pw *JobsWorker) summarize(jobObject *m.JobSchema) {
+// jobCondition returns the Job's condition of the given type, or nil if it isn't set yet.
+func jobCondition(j *batchTypes.Job, condType batchTypes.JobConditionType) *batchTypes.JobCondition {
+	for i := range j.Status.Conditions {
+		c := &j.Status.Conditions[i]
+		if c.Type == condType {
+			return c
+		}
+	}
+	return nil
+}
+
+func isJobComplete(j *batchTypes.Job) bool {
+	c := jobCondition(j, batchTypes.JobComplete)
+	return c != nil && c.Status == v1.ConditionTrue
+}
+
+func isJobFailed(j *batchTypes.Job) bool {
+	c := jobCondition(j, batchTypes.JobFailed)
+	return c != nil && c.Status == v1.ConditionTrue
+}
+
+func isBackoffLimitExceeded(j *batchTypes.Job) bool {
+	c := jobCondition(j, batchTypes.JobFailed)
+	return c != nil && c.Status == v1.ConditionTrue && c.Reason == "BackoffLimitExceeded"
+}
+
+// isNativelyManaged reports whether j was created by the in-tree Job
+// controller (or carries no managed-by label at all, which the upstream
+// convention treats the same way) rather than an external reconciler such as
+// Kueue or Armada. bag.JobManagedByFilter lists the managed-by values this
+// agent should attribute to the native job-controller counters.
+func (pw *JobsWorker) isNativelyManaged(j *batchTypes.Job) bool {
+	managedBy := j.Labels[managedByLabel]
+	for _, allowed := range pw.Bag.JobManagedByFilter {
+		if managedBy == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// summarizeExternallyManaged buckets a Job owned by an external reconciler
+// (Kueue, Armada, ...) into its own per-managed-by series instead of the
+// native job-controller counters, so operators can tell the two apart
+// instead of getting duplicate or blended counts.
+func (pw *JobsWorker) summarizeExternallyManaged(jobObject *m.JobSchema) {
+	pw.summaryMu.Lock()
+	defer pw.summaryMu.Unlock()
+
+	key := externalManagedByKey(jobObject.ManagedBy, jobObject.Namespace)
+	summary, ok := pw.SummaryMap[key]
+	if !ok {
+		summary = m.NewClusterJobMetrics(pw.Bag, jobObject.ManagedBy, jobObject.Namespace)
+	}
+
+	summary.JobCount++
+	summary.ActiveCount += int64(jobObject.Active)
+	summary.FailedCount += int64(jobObject.Failed)
+	summary.SuccessCount += int64(jobObject.Success)
+	summary.Duration += int64(jobObject.Duration)
+
+	pw.SummaryMap[key] = summary
+}
+
+// externalManagedByKey scopes SummaryMap entries for externally-managed Jobs
+// by both managed-by value and namespace, so they can never collide with the
+// native m.ALL/namespace keys that summarize uses, nor blend Jobs from
+// different namespaces that happen to share a managed-by value.
+func externalManagedByKey(managedBy string, namespace string) string {
+	return "managed-by:" + managedBy + ":" + namespace
+}
+
+func (pw *JobsWorker) summarize(jobObject *m.JobSchema, j *batchTypes.Job) {
+	pw.summaryMu.Lock()
+	defer pw.summaryMu.Unlock()
+
 	//global metrics
 	summary, ok := pw.SummaryMap[m.ALL]
 	if !ok {
@@ -225,9 +417,36 @@ func (pw *JobsWorker) summarize(jobObject *m.JobSchema) {
 	summaryNS.FailedCount += int64(jobObject.Failed)
 	summaryNS.SuccessCount += int64(jobObject.Success)
 	summaryNS.Duration += int64(jobObject.Duration)
+
+	if isJobComplete(j) {
+		summary.CompletedCount++
+		summaryNS.CompletedCount++
+
+		summary.CompletionSecondsTotal += int64(jobObject.Duration)
+		summaryNS.CompletionSecondsTotal += int64(jobObject.Duration)
+
+		summary.AvgCompletionSeconds = summary.CompletionSecondsTotal / summary.CompletedCount
+		summaryNS.AvgCompletionSeconds = summaryNS.CompletionSecondsTotal / summaryNS.CompletedCount
+	}
+
+	if isJobFailed(j) {
+		summary.JobsFailedCount++
+		summaryNS.JobsFailedCount++
+	}
+
+	if isBackoffLimitExceeded(j) {
+		summary.BackoffExceededCount++
+		summaryNS.BackoffExceededCount++
+	}
+
+	pw.SummaryMap[m.ALL] = summary
+	pw.SummaryMap[jobObject.Namespace] = summaryNS
 }
 
 func (pw JobsWorker) builAppDMetricsList() m.AppDMetricList {
+	pw.summaryMu.Lock()
+	defer pw.summaryMu.Unlock()
+
 	ml := m.NewAppDMetricList()
 	var list []m.AppDMetric
 	for _, value := range pw.SummaryMap {
@@ -265,43 +484,102 @@ func (pw *JobsWorker) eventQueueTicker(stop <-chan struct{}, ticker *time.Ticker
 	}
 }
 
+// flushQueue drains the event workqueue for this pass and ships at most
+// Bag.EventAPILimit records to the configured sinks, allocated fairly across
+// namespaces so one namespace's burst of short-lived Jobs can't starve every
+// other namespace or blow through the ingestion quota on its own.
 func (pw *JobsWorker) flushQueue() {
 	bth := pw.AppdController.StartBT("FlushJobEventsQueue")
+	defer pw.AppdController.StopBT(bth)
+
 	count := pw.WQ.Len()
-	fmt.Printf("Flushing the queue of %d records", count)
 	if count == 0 {
 		return
 	}
+	fmt.Printf("Flushing the queue of %d records\n", count)
 
-	var objList []m.JobSchema
-	var jobRecord *m.JobSchema
-	var ok bool = true
+	byNamespace := make(map[string][]*m.JobSchema)
+	for i := 0; i < count; i++ {
+		jobRecord, ok := pw.getNextQueueItem()
+		if !ok {
+			fmt.Println("Queue shut down")
+			break
+		}
+		byNamespace[jobRecord.Namespace] = append(byNamespace[jobRecord.Namespace], jobRecord)
+		pw.fairShare.trackDequeue(jobRecord.Namespace, 1)
+	}
 
-	for count >= 0 {
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns, records := range byNamespace {
+		namespaces = append(namespaces, ns)
+		pw.fairShare.observe(ns, len(records))
+	}
+	allowance := pw.fairShare.allocate(pw.Bag.EventAPILimit, namespaces)
 
-		jobRecord, ok = pw.getNextQueueItem()
-		count = count - 1
-		if ok {
-			objList = append(objList, *jobRecord)
-		} else {
-			fmt.Println("Queue shut down")
+	var objList []m.JobSchema
+	for ns, records := range byNamespace {
+		take := allowance[ns]
+		if take > len(records) {
+			take = len(records)
 		}
-		if count == 0 || len(objList) >= pw.Bag.EventAPILimit {
-			fmt.Printf("Sending %d records to AppD events API\n", len(objList))
-			pw.postJobRecords(&objList)
-			return
+		for _, rec := range records[:take] {
+			objList = append(objList, *rec)
+		}
+		if deferred := records[take:]; len(deferred) > 0 {
+			pw.recordThrottling(ns, deferred)
 		}
 	}
-	pw.AppdController.StopBT(bth)
+
+	if len(objList) > 0 {
+		fmt.Printf("Sending %d records to AppD events API\n", len(objList))
+		pw.postJobRecords(&objList)
+	}
+}
+
+// recordThrottling re-queues records that didn't get a fair-share token this
+// pass so they're retried next pass (deferred), unless the namespace's
+// backlog has already grown past a safety cap, in which case they're
+// dropped outright to bound memory use. Both counts are tallied into
+// SummaryMap so operators can see which namespaces are being throttled.
+func (pw *JobsWorker) recordThrottling(namespace string, records []*m.JobSchema) {
+	const maxBacklogPerNamespace = 1000
+
+	pw.summaryMu.Lock()
+	defer pw.summaryMu.Unlock()
+
+	summary, ok := pw.SummaryMap[m.ALL]
+	if !ok {
+		summary = m.NewClusterJobMetrics(pw.Bag, m.ALL, m.ALL)
+	}
+	summaryNS, ok := pw.SummaryMap[namespace]
+	if !ok {
+		summaryNS = m.NewClusterJobMetrics(pw.Bag, m.ALL, namespace)
+	}
+
+	for _, rec := range records {
+		if pw.fairShare.backlogLen(namespace) >= maxBacklogPerNamespace {
+			summary.DroppedCount++
+			summaryNS.DroppedCount++
+			continue
+		}
+		pw.WQ.Add(rec)
+		pw.fairShare.trackEnqueue(namespace, 1)
+		summary.DeferredCount++
+		summaryNS.DeferredCount++
+	}
+
+	pw.SummaryMap[m.ALL] = summary
+	pw.SummaryMap[namespace] = summaryNS
 }
 
 func (pw *JobsWorker) postJobRecords(objList *[]m.JobSchema) {
-	logger := log.New(os.Stdout, "[APPD_CLUSTER_MONITOR]", log.Lshortfile)
-	rc := app.NewRestClient(pw.Bag, logger)
+	rc := pw.RestClient
 	data, err := json.Marshal(objList)
-	schemaDefObj := m.NewPodSchemaDefWrapper()
-	schemaDef, e := json.Marshal(schemaDefObj)
+
+	generatedDef := m.GenerateSchemaDef(&m.JobSchema{})
+	schemaDef, e := m.MarshalSchemaDef(generatedDef)
 	fmt.Printf("Schema def: %s\n", string(schemaDef))
+
 	if err == nil && e == nil {
 		if rc.SchemaExists(pw.Bag.JobSchemaName) == false {
 			fmt.Printf("Creating schema. %s\n", pw.Bag.JobSchemaName)
@@ -310,11 +588,47 @@ func (pw *JobsWorker) postJobRecords(objList *[]m.JobSchema) {
 			}
 		} else {
 			fmt.Printf("Schema %s exists\n", pw.Bag.JobSchemaName)
+			pw.migrateSchema(rc, generatedDef)
 		}
 		fmt.Println("About to post records")
-		rc.PostAppDEvents(pw.Bag.JobSchemaName, data)
+		sinks.FanOutEvents(pw.Sinks, pw.Bag.JobSchemaName, data)
 	} else {
-		fmt.Printf("Problems when serializing array of pod schemas. %v", err)
+		fmt.Printf("Problems when serializing array of job schemas. %v", err)
+	}
+}
+
+// migrateSchema keeps the remote Analytics schema for JobSchemaName in sync
+// with JobSchema: it fetches what's registered today, diffs it against the
+// schema generated from the current struct, and patches in only the fields
+// that are new or changed, rather than letting them silently go missing.
+func (pw *JobsWorker) migrateSchema(rc *app.RestClient, generatedDef map[string]m.SchemaField) {
+	remoteData, err := rc.GetSchema(pw.Bag.JobSchemaName)
+	if err != nil {
+		fmt.Printf("Unable to fetch remote schema %s: %v\n", pw.Bag.JobSchemaName, err)
+		return
+	}
+
+	remoteDef, err := m.UnmarshalSchemaDef(remoteData)
+	if err != nil {
+		fmt.Printf("Unable to parse remote schema %s: %v\n", pw.Bag.JobSchemaName, err)
+		return
+	}
+
+	delta := m.DiffSchemaDef(generatedDef, remoteDef)
+	if len(delta) == 0 {
+		fmt.Printf("Schema %s is up to date\n", pw.Bag.JobSchemaName)
+		return
+	}
+
+	patch, err := m.MarshalSchemaDef(delta)
+	if err != nil {
+		fmt.Printf("Unable to marshal schema patch for %s: %v\n", pw.Bag.JobSchemaName, err)
+		return
+	}
+
+	fmt.Printf("Schema %s has %d new/changed field(s), patching\n", pw.Bag.JobSchemaName, len(delta))
+	if err := rc.PatchSchema(pw.Bag.JobSchemaName, patch); err != nil {
+		fmt.Printf("Unable to patch schema %s: %v\n", pw.Bag.JobSchemaName, err)
 	}
 }
 
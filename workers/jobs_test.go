@@ -0,0 +1,81 @@
+package workers
+
+import (
+	"testing"
+
+	batchTypes "k8s.io/api/batch/v1"
+	"k8s.io/api/core/v1"
+)
+
+func jobWithCondition(condType batchTypes.JobConditionType, status v1.ConditionStatus, reason string) *batchTypes.Job {
+	return &batchTypes.Job{
+		Status: batchTypes.JobStatus{
+			Conditions: []batchTypes.JobCondition{
+				{Type: condType, Status: status, Reason: reason},
+			},
+		},
+	}
+}
+
+func TestIsJobComplete(t *testing.T) {
+	cases := []struct {
+		name string
+		job  *batchTypes.Job
+		want bool
+	}{
+		{"complete condition true", jobWithCondition(batchTypes.JobComplete, v1.ConditionTrue, ""), true},
+		{"complete condition false", jobWithCondition(batchTypes.JobComplete, v1.ConditionFalse, ""), false},
+		{"no conditions", &batchTypes.Job{}, false},
+		{"only a failed condition", jobWithCondition(batchTypes.JobFailed, v1.ConditionTrue, ""), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isJobComplete(c.job); got != c.want {
+				t.Errorf("isJobComplete() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsJobFailed(t *testing.T) {
+	cases := []struct {
+		name string
+		job  *batchTypes.Job
+		want bool
+	}{
+		{"failed condition true", jobWithCondition(batchTypes.JobFailed, v1.ConditionTrue, ""), true},
+		{"failed condition false", jobWithCondition(batchTypes.JobFailed, v1.ConditionFalse, ""), false},
+		{"no conditions", &batchTypes.Job{}, false},
+		{"only a complete condition", jobWithCondition(batchTypes.JobComplete, v1.ConditionTrue, ""), false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isJobFailed(c.job); got != c.want {
+				t.Errorf("isJobFailed() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}
+
+func TestIsBackoffLimitExceeded(t *testing.T) {
+	cases := []struct {
+		name string
+		job  *batchTypes.Job
+		want bool
+	}{
+		{"backoff limit exceeded", jobWithCondition(batchTypes.JobFailed, v1.ConditionTrue, "BackoffLimitExceeded"), true},
+		{"failed for another reason", jobWithCondition(batchTypes.JobFailed, v1.ConditionTrue, "DeadlineExceeded"), false},
+		{"failed condition not true", jobWithCondition(batchTypes.JobFailed, v1.ConditionFalse, "BackoffLimitExceeded"), false},
+		{"no conditions", &batchTypes.Job{}, false},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := isBackoffLimitExceeded(c.job); got != c.want {
+				t.Errorf("isBackoffLimitExceeded() = %v, want %v", got, c.want)
+			}
+		})
+	}
+}